@@ -6,13 +6,19 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
-	"github.com/bigstats/proxysix/pkg/client"
+	"github.com/ferux/proxysix/pkg/client"
+	"github.com/ferux/proxysix/pkg/entities"
+	"github.com/ferux/proxysix/pkg/health"
+	"github.com/ferux/proxysix/pkg/store"
 	"github.com/urfave/cli/v2"
 )
 
 const defaultConfigPath = "config.json"
 
+const defaultCachePath = "proxycli.cache.json"
+
 type Config struct {
 	Key string `json:"key"`
 }
@@ -24,6 +30,9 @@ func main() {
 		info: &simpleLogger{
 			level: "info",
 		},
+		warn: &simpleLogger{
+			level: "warn",
+		},
 		error: &simpleLogger{
 			level: "error",
 		},
@@ -76,59 +85,450 @@ func main() {
 
 			return nil
 		},
-		Commands: []*cli.Command{{
-			Name: "list",
-			Flags: []cli.Flag{
-				&cli.StringFlag{
-					Name:  "desc",
-					Usage: "Filters by descr param",
-				},
-				&cli.StringFlag{
-					Name:  "state",
-					Usage: "Filters by state",
-					Value: "active",
+		Commands: []*cli.Command{
+			{
+				Name: "list",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "desc",
+						Usage: "Filters by descr param",
+					},
+					&cli.StringFlag{
+						Name:  "state",
+						Usage: "Filters by state",
+						Value: "active",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					descr := cCtx.String("desc")
+					if descr != "" {
+						log.Debug("using description filter: %s", descr)
+					}
+
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					state := client.ProxyStateAll
+					if stateStr := cCtx.String("state"); stateStr != "" {
+						log.Debug("using state %s", stateStr)
+						state, err = client.ParseProxyState(stateStr)
+						if err != nil {
+							return fmt.Errorf("parsing proxy state: %w", err)
+						}
+					}
+
+					proxies, err := proxyClient.ListProxies(ctx, client.ListProxyParams{
+						Descr: descr,
+						State: state,
+					})
+					if err != nil {
+						return fmt.Errorf("getting active proxies: %w", err)
+					}
+
+					printProxies(log, proxies)
+
+					return nil
 				},
 			},
-			Action: func(cCtx *cli.Context) error {
-				descr := cCtx.String("desc")
-				if descr != "" {
-					log.Debug("using description filter: %s", descr)
-				}
+			{
+				Name:  "buy",
+				Usage: "Buy new proxies",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "count", Value: 1, Usage: "How many proxies to buy"},
+					&cli.IntFlag{Name: "period", Value: 30, Usage: "Lease period in days"},
+					&cli.StringFlag{Name: "country", Usage: "Country code, e.g. ru"},
+					&cli.StringFlag{Name: "version", Value: "4", Usage: "Proxy version: 4 or 6"},
+					&cli.StringFlag{Name: "descr", Usage: "Description attached to the purchased proxies"},
+					&cli.StringFlag{Name: "auth-ip", Usage: "Authenticate purchased proxies by IP instead of login/password"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
 
-				ctx := cCtx.Context
-				proxyClient, err := client.NewHTTPClient(
-					ctx, config.Key,
-					client.WithLoggerFunc(getLogger),
-				)
-				if err != nil {
-					return fmt.Errorf("making new http client: %w", err)
-				}
+					version, err := entities.ParseProxyVersion(cCtx.String("version"))
+					if err != nil {
+						return fmt.Errorf("parsing proxy version: %w", err)
+					}
 
-				state := client.ProxyStateAll
-				if stateStr := cCtx.String("state"); stateStr != "" {
-					log.Debug("using state %s", stateStr)
-					state, err = client.ParseProxyState(stateStr)
+					proxies, err := proxyClient.Buy(ctx, client.BuyParams{
+						Count:   cCtx.Int("count"),
+						Period:  cCtx.Int("period"),
+						Country: cCtx.String("country"),
+						Version: version,
+						Descr:   cCtx.String("descr"),
+						AuthIP:  cCtx.String("auth-ip"),
+					})
 					if err != nil {
-						return fmt.Errorf("parsing proxy state: %w", err)
+						return fmt.Errorf("buying proxies: %w", err)
 					}
-				}
 
-				proxies, err := proxyClient.ListProxies(ctx, client.ListProxyParams{
-					Descr: descr,
-					State: state,
-				})
-				if err != nil {
-					return fmt.Errorf("getting active proxies: %w", err)
-				}
+					printProxies(log, proxies)
 
-				for _, proxy := range proxies {
-					proxyJSON, _ := json.Marshal(proxy)
-					log.Info("%s", proxyJSON)
-				}
+					return nil
+				},
+			},
+			{
+				Name:  "prolong",
+				Usage: "Extend the lease of existing proxies",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{Name: "id", Usage: "Proxy id, can be repeated"},
+					&cli.IntFlag{Name: "period", Value: 30, Usage: "Extra days to add"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
 
-				return nil
+					proxies, err := proxyClient.Prolong(ctx, client.ProlongParams{
+						IDs:    cCtx.StringSlice("id"),
+						Period: cCtx.Int("period"),
+					})
+					if err != nil {
+						return fmt.Errorf("prolonging proxies: %w", err)
+					}
+
+					printProxies(log, proxies)
+
+					return nil
+				},
 			},
-		}},
+			{
+				Name:  "delete",
+				Usage: "Delete proxies",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{Name: "id", Usage: "Proxy id, can be repeated"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					if err := proxyClient.Delete(ctx, client.DeleteParams{IDs: cCtx.StringSlice("id")}); err != nil {
+						return fmt.Errorf("deleting proxies: %w", err)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "check",
+				Usage: "Check whether a proxy is reachable",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "id", Required: true, Usage: "Proxy id"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					result, err := proxyClient.Check(ctx, client.CheckParams{ID: cCtx.String("id")})
+					if err != nil {
+						return fmt.Errorf("checking proxy: %w", err)
+					}
+
+					resultJSON, _ := json.Marshal(result)
+					log.Info("%s", resultJSON)
+
+					return nil
+				},
+			},
+			{
+				Name:  "setdescr",
+				Usage: "Rename the description attached to proxies",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{Name: "id", Usage: "Proxy id, can be repeated"},
+					&cli.StringFlag{Name: "old", Usage: "Select proxies by their current description instead of id"},
+					&cli.StringFlag{Name: "new", Required: true, Usage: "New description"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					err = proxyClient.SetDescr(ctx, client.SetDescrParams{
+						IDs: cCtx.StringSlice("id"),
+						Old: cCtx.String("old"),
+						New: cCtx.String("new"),
+					})
+					if err != nil {
+						return fmt.Errorf("setting description: %w", err)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "getcount",
+				Usage: "Show how many proxies are available for purchase",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "country", Usage: "Country code, e.g. ru"},
+					&cli.StringFlag{Name: "version", Value: "4", Usage: "Proxy version: 4 or 6"},
+					&cli.IntFlag{Name: "period", Value: 30, Usage: "Lease period in days"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					version, err := entities.ParseProxyVersion(cCtx.String("version"))
+					if err != nil {
+						return fmt.Errorf("parsing proxy version: %w", err)
+					}
+
+					count, err := proxyClient.GetCount(ctx, client.GetCountParams{
+						Country: cCtx.String("country"),
+						Version: version,
+						Period:  cCtx.Int("period"),
+					})
+					if err != nil {
+						return fmt.Errorf("getting count: %w", err)
+					}
+
+					log.Info("%d", count)
+
+					return nil
+				},
+			},
+			{
+				Name:  "getprice",
+				Usage: "Quote the price of buying proxies",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "count", Value: 1, Usage: "How many proxies to price"},
+					&cli.IntFlag{Name: "period", Value: 30, Usage: "Lease period in days"},
+					&cli.StringFlag{Name: "version", Value: "4", Usage: "Proxy version: 4 or 6"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					version, err := entities.ParseProxyVersion(cCtx.String("version"))
+					if err != nil {
+						return fmt.Errorf("parsing proxy version: %w", err)
+					}
+
+					quote, err := proxyClient.GetPrice(ctx, client.GetPriceParams{
+						Count:   cCtx.Int("count"),
+						Period:  cCtx.Int("period"),
+						Version: version,
+					})
+					if err != nil {
+						return fmt.Errorf("getting price: %w", err)
+					}
+
+					quoteJSON, _ := json.Marshal(quote)
+					log.Info("%s", quoteJSON)
+
+					return nil
+				},
+			},
+			{
+				Name:  "getcountry",
+				Usage: "List countries available for purchase",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "version", Value: "4", Usage: "Proxy version: 4 or 6"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					version, err := entities.ParseProxyVersion(cCtx.String("version"))
+					if err != nil {
+						return fmt.Errorf("parsing proxy version: %w", err)
+					}
+
+					countries, err := proxyClient.GetCountry(ctx, client.GetCountryParams{Version: version})
+					if err != nil {
+						return fmt.Errorf("getting countries: %w", err)
+					}
+
+					for _, country := range countries {
+						log.Info("%s", country.Code)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "cache",
+				Usage: "Query and refresh the local proxy cache",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "sync",
+						Usage: "Refresh the local cache from the proxy6 API",
+						Flags: []cli.Flag{
+							&cli.PathFlag{Name: "cache", Value: defaultCachePath, Usage: "Path to the cache file"},
+							&cli.StringFlag{Name: "desc", Usage: "Filters by descr param"},
+							&cli.StringFlag{Name: "state", Value: "active", Usage: "Filters by state"},
+						},
+						Action: func(cCtx *cli.Context) error {
+							ctx := cCtx.Context
+							proxyClient, err := newClient(ctx, config)
+							if err != nil {
+								return err
+							}
+
+							cacheStore, err := newStore(cCtx.Path("cache"), config)
+							if err != nil {
+								return err
+							}
+
+							state, err := client.ParseProxyState(cCtx.String("state"))
+							if err != nil {
+								return fmt.Errorf("parsing proxy state: %w", err)
+							}
+
+							events, err := cacheStore.Sync(ctx, proxyClient, client.ListProxyParams{
+								Descr: cCtx.String("desc"),
+								State: state,
+							})
+							if err != nil {
+								return fmt.Errorf("syncing cache: %w", err)
+							}
+
+							for _, event := range events {
+								log.Info("%d %s", event.Kind, event.Proxy.ID)
+							}
+
+							return nil
+						},
+					},
+					{
+						Name:  "list",
+						Usage: "List proxies from the local cache without calling the API",
+						Flags: []cli.Flag{
+							&cli.PathFlag{Name: "cache", Value: defaultCachePath, Usage: "Path to the cache file"},
+							&cli.StringFlag{Name: "country", Usage: "Filters by country"},
+							&cli.StringFlag{Name: "desc", Usage: "Filters by description substring"},
+						},
+						Action: func(cCtx *cli.Context) error {
+							cacheStore, err := newStore(cCtx.Path("cache"), config)
+							if err != nil {
+								return err
+							}
+
+							ctx := cCtx.Context
+
+							var proxies []entities.Proxy
+							switch {
+							case cCtx.String("country") != "":
+								proxies, err = cacheStore.GetByCountry(ctx, cCtx.String("country"))
+							case cCtx.String("desc") != "":
+								proxies, err = cacheStore.Search(ctx, cCtx.String("desc"))
+							default:
+								proxies, err = cacheStore.ListAll(ctx)
+							}
+							if err != nil {
+								return fmt.Errorf("listing cached proxies: %w", err)
+							}
+
+							printProxies(log, proxies)
+
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "ipauth",
+				Usage: "Switch proxies to IP-based authentication",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{Name: "id", Usage: "Proxy id, can be repeated"},
+					&cli.StringFlag{Name: "ip", Required: true, Usage: "IP address allowed to use the proxies without credentials"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					err = proxyClient.IPAuth(ctx, client.IPAuthParams{
+						IDs: cCtx.StringSlice("id"),
+						IP:  cCtx.String("ip"),
+					})
+					if err != nil {
+						return fmt.Errorf("setting ip auth: %w", err)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "health",
+				Usage: "Probe active proxies and print a reachability table; exits non-zero if any proxy fails",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "probe", Value: "tcp", Usage: "Probe type: tcp, http, or dns"},
+					&cli.StringFlag{Name: "canary-url", Value: "https://api.ipify.org", Usage: "URL fetched through the proxy when probe is http"},
+					&cli.StringFlag{Name: "canary-host", Value: "proxy6.net", Usage: "Host resolved through the proxy when probe is dns"},
+					&cli.DurationFlag{Name: "timeout", Value: 5 * time.Second, Usage: "Per-proxy probe timeout"},
+					&cli.IntFlag{Name: "concurrency", Value: 8, Usage: "How many proxies to probe at once"},
+				},
+				Action: func(cCtx *cli.Context) error {
+					ctx := cCtx.Context
+					proxyClient, err := newClient(ctx, config)
+					if err != nil {
+						return err
+					}
+
+					proxies, err := proxyClient.ListProxies(ctx, client.ListProxyParams{State: client.ProxyStateActive})
+					if err != nil {
+						return fmt.Errorf("getting active proxies: %w", err)
+					}
+
+					probeType, err := health.ParseProbeType(cCtx.String("probe"))
+					if err != nil {
+						return fmt.Errorf("parsing probe type: %w", err)
+					}
+
+					checker := health.New(proxies, health.Config{
+						Concurrency: cCtx.Int("concurrency"),
+						Timeout:     cCtx.Duration("timeout"),
+						Probe:       probeType,
+						CanaryURL:   cCtx.String("canary-url"),
+						CanaryHost:  cCtx.String("canary-host"),
+					})
+
+					unreachable := 0
+					for _, event := range checker.Check(ctx) {
+						status := "ok"
+						if !event.OK {
+							status = "fail"
+							unreachable++
+						}
+
+						log.Info("%s\t%s\t%s\t%v\t%v", event.ProxyID, status, event.EgressIP, event.Latency, event.Err)
+					}
+
+					if unreachable > 0 {
+						return fmt.Errorf("%d of %d proxies unreachable", unreachable, len(proxies))
+					}
+
+					return nil
+				},
+			},
+		},
 	}
 
 	err := app.Run(os.Args)
@@ -138,9 +538,38 @@ func main() {
 	}
 }
 
+func newClient(ctx context.Context, config Config) (client.Client, error) {
+	proxyClient, err := client.NewHTTPClient(
+		ctx, config.Key,
+		client.WithLoggerFunc(getLogger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("making new http client: %w", err)
+	}
+
+	return proxyClient, nil
+}
+
+func newStore(cachePath string, config Config) (*store.Store, error) {
+	kv, err := store.NewFileKV(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening cache %s: %w", cachePath, err)
+	}
+
+	return store.New(kv, config.Key), nil
+}
+
+func printProxies(log log, proxies []entities.Proxy) {
+	for _, proxy := range proxies {
+		proxyJSON, _ := json.Marshal(proxy)
+		log.Info("%s", proxyJSON)
+	}
+}
+
 type log struct {
 	debug *simpleLogger
 	info  *simpleLogger
+	warn  *simpleLogger
 	error *simpleLogger
 }
 
@@ -164,6 +593,10 @@ func (l log) Info(format string, args ...any) {
 	l.info.Log(format, args...)
 }
 
+func (l log) Warn(format string, args ...any) {
+	l.warn.Log(format, args...)
+}
+
 func (l log) Error(format string, args ...any) {
 	l.error.Log(format, args...)
 }
@@ -180,12 +613,13 @@ func getLogger(ctx context.Context) client.Logger {
 }
 
 type simpleWrapper struct {
-	log log
+	log    log
+	fields []client.KeyValue
 }
 
 func (sw simpleWrapper) join(args ...client.KeyValue) string {
 	var s strings.Builder
-	for _, kv := range args {
+	for _, kv := range append(append([]client.KeyValue{}, sw.fields...), args...) {
 		text := fmt.Sprintf("%s=%v", kv.Key, kv.Value)
 		s.WriteString(text + " ")
 	}
@@ -194,14 +628,42 @@ func (sw simpleWrapper) join(args ...client.KeyValue) string {
 }
 
 func (sw simpleWrapper) Debug(msg string, args ...client.KeyValue) {
-	sw.log.debug.Log(msg + " " + sw.join(args...))
+	sw.log.debug.Log("%s", msg+" "+sw.join(args...))
 }
 func (sw simpleWrapper) Info(msg string, args ...client.KeyValue) {
-	sw.log.info.Log(msg + " " + sw.join(args...))
+	sw.log.info.Log("%s", msg+" "+sw.join(args...))
+}
+
+func (sw simpleWrapper) Warn(msg string, args ...client.KeyValue) {
+	sw.log.warn.Log("%s", msg+" "+sw.join(args...))
 }
 
 func (sw simpleWrapper) Error(msg string, args ...client.KeyValue) {
-	sw.log.error.Log(msg + " " + sw.join(args...))
+	sw.log.error.Log("%s", msg+" "+sw.join(args...))
+}
+
+// With returns a scoped wrapper that prepends fields to every subsequent
+// log call, demonstrating client.LoggerV2 alongside the zerolog and slog
+// adapters.
+func (sw simpleWrapper) With(fields ...client.KeyValue) client.LoggerV2 {
+	return simpleWrapper{
+		log:    sw.log,
+		fields: append(append([]client.KeyValue{}, sw.fields...), fields...),
+	}
+}
+
+// StartSpan times the block between here and the returned EndFunc and logs
+// it as a single info line carrying the span name and duration.
+func (sw simpleWrapper) StartSpan(ctx context.Context, name string) (context.Context, client.EndFunc) {
+	begin := time.Now()
+
+	return ctx, func(fields ...client.KeyValue) {
+		all := append([]client.KeyValue{
+			client.LogField("span", name),
+			client.LogField("duration", time.Since(begin)),
+		}, fields...)
+		sw.Info("span end", all...)
+	}
 }
 
 func wrapLogger(log log) client.Logger {
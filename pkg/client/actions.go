@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ferux/proxysix/pkg/entities"
+)
+
+// BuyParams describes a purchase of new proxies via the buy action.
+type BuyParams struct {
+	Count   int
+	Period  int
+	Country string
+	Version entities.ProxyVersion
+	Descr   string
+	AuthIP  string
+}
+
+func (c *httpClient) Buy(ctx context.Context, params BuyParams) ([]entities.Proxy, error) {
+	values := make(url.Values, 6)
+	values.Set("count", strconv.Itoa(params.Count))
+	values.Set("period", strconv.Itoa(params.Period))
+	values.Set("country", params.Country)
+	values.Set("descr", params.Descr)
+	if params.AuthIP != "" {
+		values.Set("auth_ip", params.AuthIP)
+	}
+	if params.Version != entities.ProxyVersionUnknown {
+		setVersion(values, params.Version)
+	}
+
+	var response proxyResponse
+	if err := c.call(ctx, "buy", values, &response); err != nil {
+		return nil, err
+	}
+
+	if response.isError() {
+		return nil, RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	return mapProxyResponse(response)
+}
+
+// ProlongParams extends the lifetime of existing proxies via the prolong
+// action.
+type ProlongParams struct {
+	IDs    []string
+	Period int
+}
+
+func (c *httpClient) Prolong(ctx context.Context, params ProlongParams) ([]entities.Proxy, error) {
+	values := make(url.Values, 2)
+	values.Set("period", strconv.Itoa(params.Period))
+	values.Set("ids", strings.Join(params.IDs, ","))
+
+	var response proxyResponse
+	if err := c.call(ctx, "prolong", values, &response); err != nil {
+		return nil, err
+	}
+
+	if response.isError() {
+		return nil, RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	return mapProxyResponse(response)
+}
+
+// DeleteParams removes proxies via the delete action.
+type DeleteParams struct {
+	IDs []string
+}
+
+func (c *httpClient) Delete(ctx context.Context, params DeleteParams) error {
+	values := make(url.Values, 1)
+	values.Set("ids", strings.Join(params.IDs, ","))
+
+	var response baseResponse
+	if err := c.call(ctx, "delete", values, &response); err != nil {
+		return err
+	}
+
+	if response.isError() {
+		return RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	return nil
+}
+
+// CheckParams probes a single proxy's reachability via the check action.
+type CheckParams struct {
+	ID string
+}
+
+type checkResponse struct {
+	baseResponse
+
+	ProxyID     string `json:"proxy_id"`
+	ProxyStatus bool   `json:"proxy_status"`
+}
+
+func (c *httpClient) Check(ctx context.Context, params CheckParams) (entities.ProxyCheck, error) {
+	values := make(url.Values, 1)
+	values.Set("ids", params.ID)
+
+	var response checkResponse
+	if err := c.call(ctx, "check", values, &response); err != nil {
+		return entities.ProxyCheck{}, err
+	}
+
+	if response.isError() {
+		return entities.ProxyCheck{}, RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	return entities.ProxyCheck{ID: response.ProxyID, OK: response.ProxyStatus}, nil
+}
+
+// SetDescrParams renames the description attached to one or more proxies.
+// Either IDs or Old must be set to select the proxies to rename.
+type SetDescrParams struct {
+	IDs []string
+	Old string
+	New string
+}
+
+func (c *httpClient) SetDescr(ctx context.Context, params SetDescrParams) error {
+	values := make(url.Values, 2)
+	values.Set("new", params.New)
+	if len(params.IDs) > 0 {
+		values.Set("ids", strings.Join(params.IDs, ","))
+	}
+	if params.Old != "" {
+		values.Set("old", params.Old)
+	}
+
+	var response baseResponse
+	if err := c.call(ctx, "setdescr", values, &response); err != nil {
+		return err
+	}
+
+	if response.isError() {
+		return RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	return nil
+}
+
+// GetCountParams asks proxy6 how many proxies are available for the given
+// country/version/period combination.
+type GetCountParams struct {
+	Country string
+	Version entities.ProxyVersion
+	Period  int
+}
+
+type getCountResponse struct {
+	baseResponse
+
+	Count int `json:"count"`
+}
+
+func (c *httpClient) GetCount(ctx context.Context, params GetCountParams) (int, error) {
+	values := make(url.Values, 3)
+	values.Set("country", params.Country)
+	values.Set("period", strconv.Itoa(params.Period))
+	if params.Version != entities.ProxyVersionUnknown {
+		setVersion(values, params.Version)
+	}
+
+	var response getCountResponse
+	if err := c.call(ctx, "getcount", values, &response); err != nil {
+		return 0, err
+	}
+
+	if response.isError() {
+		return 0, RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	return response.Count, nil
+}
+
+// GetPriceParams requests a price quote for buying count proxies for period
+// days.
+type GetPriceParams struct {
+	Count   int
+	Period  int
+	Version entities.ProxyVersion
+}
+
+type getPriceResponse struct {
+	baseResponse
+
+	Price       float64 `json:"price"`
+	PriceSingle float64 `json:"price_single"`
+	Period      int     `json:"period"`
+	Count       int     `json:"count"`
+}
+
+func (c *httpClient) GetPrice(ctx context.Context, params GetPriceParams) (entities.PriceQuote, error) {
+	values := make(url.Values, 3)
+	values.Set("count", strconv.Itoa(params.Count))
+	values.Set("period", strconv.Itoa(params.Period))
+	if params.Version != entities.ProxyVersionUnknown {
+		setVersion(values, params.Version)
+	}
+
+	var response getPriceResponse
+	if err := c.call(ctx, "getprice", values, &response); err != nil {
+		return entities.PriceQuote{}, err
+	}
+
+	if response.isError() {
+		return entities.PriceQuote{}, RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	return entities.PriceQuote{
+		Price:       response.Price,
+		PriceSingle: response.PriceSingle,
+		Period:      response.Period,
+		Count:       response.Count,
+	}, nil
+}
+
+// GetCountryParams filters the countries returned by the getcountry action.
+type GetCountryParams struct {
+	Version entities.ProxyVersion
+}
+
+type getCountryResponse struct {
+	baseResponse
+
+	List []string `json:"list"`
+}
+
+func (c *httpClient) GetCountry(ctx context.Context, params GetCountryParams) ([]entities.Country, error) {
+	values := make(url.Values, 1)
+	if params.Version != entities.ProxyVersionUnknown {
+		setVersion(values, params.Version)
+	}
+
+	var response getCountryResponse
+	if err := c.call(ctx, "getcountry", values, &response); err != nil {
+		return nil, err
+	}
+
+	if response.isError() {
+		return nil, RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	countries := make([]entities.Country, 0, len(response.List))
+	for _, code := range response.List {
+		countries = append(countries, entities.Country{Code: code})
+	}
+
+	return countries, nil
+}
+
+// IPAuthParams switches one or more proxies to IP-based authentication,
+// dropping the user/password requirement for the given IP.
+type IPAuthParams struct {
+	IDs []string
+	IP  string
+}
+
+func (c *httpClient) IPAuth(ctx context.Context, params IPAuthParams) error {
+	values := make(url.Values, 2)
+	values.Set("ip", params.IP)
+	if len(params.IDs) > 0 {
+		values.Set("ids", strings.Join(params.IDs, ","))
+	}
+
+	var response baseResponse
+	if err := c.call(ctx, "ipauth", values, &response); err != nil {
+		return err
+	}
+
+	if response.isError() {
+		return RequestError{code: response.ErrorID, msg: response.Error}
+	}
+
+	return nil
+}
+
+func setVersion(values url.Values, version entities.ProxyVersion) {
+	text, err := version.MarshalText()
+	if err != nil {
+		return
+	}
+
+	values.Set("version", string(text))
+}
@@ -66,6 +66,15 @@ func (s ProxyState) String() string {
 
 type Client interface {
 	ListProxies(ctx context.Context, params ListProxyParams) ([]entities.Proxy, error)
+	Buy(ctx context.Context, params BuyParams) ([]entities.Proxy, error)
+	Prolong(ctx context.Context, params ProlongParams) ([]entities.Proxy, error)
+	Delete(ctx context.Context, params DeleteParams) error
+	Check(ctx context.Context, params CheckParams) (entities.ProxyCheck, error)
+	SetDescr(ctx context.Context, params SetDescrParams) error
+	GetCount(ctx context.Context, params GetCountParams) (int, error)
+	GetPrice(ctx context.Context, params GetPriceParams) (entities.PriceQuote, error)
+	GetCountry(ctx context.Context, params GetCountryParams) ([]entities.Country, error)
+	IPAuth(ctx context.Context, params IPAuthParams) error
 }
 
 type httpClient struct {
@@ -74,6 +83,10 @@ type httpClient struct {
 
 	hclient *http.Client
 	logF    GetLogger
+
+	retry   *RetryPolicy
+	limiter *rateLimiter
+	breaker *circuitBreaker
 }
 
 type options struct {
@@ -129,6 +142,7 @@ type proxy struct {
 	User        string `json:"user"`
 	Pass        string `json:"pass"`
 	Type        string `json:"type"`
+	Version     string `json:"version"`
 	Country     string `json:"country"`
 	Date        string `json:"date"`
 	DateEnd     string `json:"date_end"`
@@ -136,6 +150,7 @@ type proxy struct {
 	UnixtimeEnd int64  `json:"unixtime_end"`
 	Descr       string `json:"descr"`
 	Active      string `json:"active"`
+	AutoProlong string `json:"auto_prolong"`
 }
 
 type baseResponse struct {
@@ -175,25 +190,12 @@ func (c *httpClient) ListProxies(ctx context.Context, params ListProxyParams) ([
 }
 
 func (c *httpClient) loadAllProxies(ctx context.Context, state ProxyState, desc string) (response proxyResponse, err error) {
-	const pathTemplate = "/api/{api_key}/getproxy"
-
-	reqURL := c.addr + strings.ReplaceAll(pathTemplate, "{api_key}", c.key)
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return response, fmt.Errorf("making request: %w", err)
-	}
-
 	values := make(url.Values, 3)
 	values.Set("state", state.String())
 	values.Set("descr", desc)
 	values.Set("nokey", "")
 
-	request.URL.RawQuery = values.Encode()
-
-	log := c.logF(ctx)
-
-	err = doRequest(c.hclient, request, log, &response)
-	if err != nil {
+	if err := c.call(ctx, "getproxy", values, &response); err != nil {
 		return response, err
 	}
 
@@ -207,14 +209,119 @@ func (c *httpClient) loadAllProxies(ctx context.Context, state ProxyState, desc
 	return response, nil
 }
 
-func doRequest(c *http.Client, req *http.Request, log Logger, dst any) error {
+// call performs a GET request against the given proxy6 action and decodes
+// the response into dst. dst must embed baseResponse so the caller can
+// inspect isError() once call returns.
+func (c *httpClient) call(ctx context.Context, action string, values url.Values, dst any) error {
+	const pathTemplate = "/api/{api_key}/"
+
+	reqURL := c.addr + strings.ReplaceAll(pathTemplate, "{api_key}", c.key) + action
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+
+	if values == nil {
+		values = make(url.Values, 1)
+	}
+	values.Set("nokey", "")
+
+	request.URL.RawQuery = values.Encode()
+
+	log := c.logF(ctx)
+
+	return doRequest(ctx, c, request, log, dst)
+}
+
+func doRequest(ctx context.Context, c *httpClient, req *http.Request, log Logger, dst any) error {
+	logV2 := AsLoggerV2(log)
+
+	ctx, end := logV2.StartSpan(ctx, "proxy6.request")
+
+	var statusCode int
+
+	retries := 0
+	defer func() {
+		fields := []KeyValue{LogField("retries", retries)}
+		if statusCode != 0 {
+			fields = append(fields, LogField("status_code", statusCode))
+		}
+
+		end(fields...)
+	}()
+
+	attempts := 1
+	if c.retry != nil {
+		attempts = c.retry.MaxAttempts
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			retries++
+
+			delay := c.retry.delay(attempt)
+			log.Debug(
+				"retry attempt",
+				LogField("attempt", attempt),
+				LogField("delay", delay),
+				LogField("last_error", lastErr),
+			)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+
+				return ctx.Err()
+			}
+		}
+
+		if c.breaker != nil && !c.breaker.Allow() {
+			return newGeneralError("circuit breaker is open")
+		}
+
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return fmt.Errorf("waiting for rate limit: %w", err)
+			}
+		}
+
+		code, err := attemptRequest(c.hclient, req, log, dst)
+		if code != 0 {
+			statusCode = code
+		}
+
+		if err == nil {
+			if c.breaker != nil {
+				c.breaker.RecordSuccess()
+			}
+
+			return nil
+		}
+
+		lastErr = err
+		if c.breaker != nil {
+			c.breaker.RecordFailure()
+		}
+
+		if c.retry == nil || !c.retry.shouldRetry(err) {
+			break
+		}
+	}
+
+	return lastErr
+}
+
+func attemptRequest(c *http.Client, req *http.Request, log Logger, dst any) (int, error) {
 	log.Debug("sending request", LogField("req_url", req.URL.String()))
 
 	begin := time.Now()
 
 	resp, err := c.Do(req)
 	if err != nil {
-		return fmt.Errorf("doing request: %w", err)
+		return 0, fmt.Errorf("doing request: %w", err)
 	}
 
 	body, _ := ioutil.ReadAll(resp.Body)
@@ -226,21 +333,18 @@ func doRequest(c *http.Client, req *http.Request, log Logger, dst any) error {
 	)
 
 	if resp.StatusCode >= http.StatusBadRequest {
-		// body, _ := ioutil.ReadAll(resp.Body)
-
-		return RequestError{
+		return resp.StatusCode, RequestError{
 			code: resp.StatusCode,
 			msg:  string(body),
 		}
 	}
 
-	// err = json.NewDecoder(resp.Body).Decode(dst)
 	err = json.Unmarshal(body, dst)
 	if err != nil {
-		return fmt.Errorf("decoding json: %w", err)
+		return resp.StatusCode, fmt.Errorf("decoding json: %w", err)
 	}
 
-	return nil
+	return resp.StatusCode, nil
 }
 
 func mapProxyResponse(response proxyResponse) ([]entities.Proxy, error) {
@@ -268,6 +372,14 @@ func mapProxyToEntity(proxy proxy) (entities.Proxy, error) {
 		return entities.Proxy{}, fmt.Errorf("parsing proxy type: %w", err)
 	}
 
+	var version entities.ProxyVersion
+	if proxy.Version != "" {
+		version, err = entities.ParseProxyVersion(proxy.Version)
+		if err != nil {
+			return entities.Proxy{}, fmt.Errorf("parsing proxy version: %w", err)
+		}
+	}
+
 	entityProxy := entities.Proxy{
 		ID:          proxy.ID,
 		Host:        proxy.Host,
@@ -275,11 +387,13 @@ func mapProxyToEntity(proxy proxy) (entities.Proxy, error) {
 		User:        proxy.User,
 		Password:    entities.NewSensitive(proxy.Pass),
 		Type:        proxyType,
+		Version:     version,
 		Country:     proxy.Country,
 		Date:        time.Unix(proxy.Unixtime, 0),
 		ExpireDate:  time.Unix(proxy.UnixtimeEnd, 0),
 		Description: proxy.Descr,
 		Active:      strings.EqualFold(proxy.Active, "1"),
+		AutoRenew:   strings.EqualFold(proxy.AutoProlong, "1"),
 	}
 
 	return entityProxy, nil
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -26,11 +27,74 @@ type Logger interface {
 	Error(msg string, args ...KeyValue)
 }
 
+// EndFunc closes a span started by LoggerV2.StartSpan, attaching any final
+// fields (status code, retry count, ...) to the emitted event.
+type EndFunc func(fields ...KeyValue)
+
+// LoggerV2 extends Logger with a Warn level, scoped loggers via With, and
+// spans, so callers can feed proxy6 client traces into OpenTelemetry or any
+// other structured sink without writing their own shim. It is a separate
+// interface rather than a breaking change to Logger so existing
+// implementations keep compiling; use AsLoggerV2 to upgrade one.
+type LoggerV2 interface {
+	Logger
+
+	Warn(msg string, args ...KeyValue)
+	With(fields ...KeyValue) LoggerV2
+	StartSpan(ctx context.Context, name string) (context.Context, EndFunc)
+}
+
+// AsLoggerV2 returns log unchanged if it already implements LoggerV2,
+// otherwise it wraps log so Warn degrades to Info and StartSpan times the
+// span itself and logs once on End.
+func AsLoggerV2(log Logger) LoggerV2 {
+	if v2, ok := log.(LoggerV2); ok {
+		return v2
+	}
+
+	return basicLoggerV2{log: log}
+}
+
+type basicLoggerV2 struct {
+	log    Logger
+	fields []KeyValue
+}
+
+func (b basicLoggerV2) Debug(msg string, args ...KeyValue) {
+	b.log.Debug(msg, append(append([]KeyValue{}, b.fields...), args...)...)
+}
+
+func (b basicLoggerV2) Info(msg string, args ...KeyValue) {
+	b.log.Info(msg, append(append([]KeyValue{}, b.fields...), args...)...)
+}
+
+func (b basicLoggerV2) Error(msg string, args ...KeyValue) {
+	b.log.Error(msg, append(append([]KeyValue{}, b.fields...), args...)...)
+}
+
+func (b basicLoggerV2) Warn(msg string, args ...KeyValue) {
+	fields := append([]KeyValue{LogField("level", "warn")}, b.fields...)
+	b.log.Info(msg, append(fields, args...)...)
+}
+
+func (b basicLoggerV2) With(fields ...KeyValue) LoggerV2 {
+	return basicLoggerV2{log: b.log, fields: append(append([]KeyValue{}, b.fields...), fields...)}
+}
+
+func (b basicLoggerV2) StartSpan(ctx context.Context, name string) (context.Context, EndFunc) {
+	begin := time.Now()
+
+	return ctx, func(fields ...KeyValue) {
+		all := append([]KeyValue{LogField("span", name), LogField("duration", time.Since(begin))}, fields...)
+		b.Info("span end", all...)
+	}
+}
+
 func GetLoggerZerolog(ctx context.Context) Logger {
 	return WrapZerolog(zerolog.Ctx(ctx))
 }
 
-func WrapZerolog(log *zerolog.Logger) Logger {
+func WrapZerolog(log *zerolog.Logger) LoggerV2 {
 	return zerologWrapper{
 		log: log.With().Logger(),
 	}
@@ -58,6 +122,15 @@ func (l zerologWrapper) Info(msg string, args ...KeyValue) {
 	event.Msg(msg)
 }
 
+func (l zerologWrapper) Warn(msg string, args ...KeyValue) {
+	event := l.log.Warn()
+	for _, arg := range args {
+		event.Interface(arg.Key, arg.Value)
+	}
+
+	event.Msg(msg)
+}
+
 func (l zerologWrapper) Error(msg string, args ...KeyValue) {
 	event := l.log.Error()
 	for _, arg := range args {
@@ -67,10 +140,40 @@ func (l zerologWrapper) Error(msg string, args ...KeyValue) {
 	event.Msg(msg)
 }
 
+func (l zerologWrapper) With(fields ...KeyValue) LoggerV2 {
+	scoped := l.log.With()
+	for _, field := range fields {
+		scoped = scoped.Interface(field.Key, field.Value)
+	}
+
+	return zerologWrapper{log: scoped.Logger()}
+}
+
+func (l zerologWrapper) StartSpan(ctx context.Context, name string) (context.Context, EndFunc) {
+	begin := time.Now()
+	scoped := l.log.With().Str("span", name).Logger()
+
+	return ctx, func(fields ...KeyValue) {
+		event := scoped.Info().Dur("duration", time.Since(begin))
+		for _, field := range fields {
+			event.Interface(field.Key, field.Value)
+		}
+
+		event.Msg("span end")
+	}
+}
+
 func GetLoggerNoop(ctx context.Context) Logger { return noopLogger{} }
 
 type noopLogger struct{}
 
 func (noopLogger) Debug(format string, args ...KeyValue) {}
 func (noopLogger) Info(format string, args ...KeyValue)  {}
+func (noopLogger) Warn(format string, args ...KeyValue)  {}
 func (noopLogger) Error(format string, args ...KeyValue) {}
+
+func (noopLogger) With(fields ...KeyValue) LoggerV2 { return noopLogger{} }
+
+func (noopLogger) StartSpan(ctx context.Context, _ string) (context.Context, EndFunc) {
+	return ctx, func(fields ...KeyValue) {}
+}
@@ -0,0 +1,227 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how doRequest retries a failed call: exponential
+// backoff with jitter between attempts, bounded by MaxAttempts.
+type RetryPolicy struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	MaxAttempts int
+}
+
+// DefaultRetryPolicy retries transient failures up to twice more, starting
+// at 200ms and capping at 5s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		MaxAttempts: 3,
+	}
+}
+
+// WithRetry enables retrying failed requests according to policy.
+// MaxAttempts must be at least 1 (1 means no retries).
+func WithRetry(policy RetryPolicy) optionF {
+	return func(hc *httpClient) error {
+		if policy.MaxAttempts < 1 {
+			return newGeneralError("retry policy: max attempts must be at least 1")
+		}
+
+		hc.retry = &policy
+
+		return nil
+	}
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.BaseDelay << uint(attempt-1)
+	if backoff <= 0 || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// shouldRetry reports whether err looks transient: a 429/5xx RequestError,
+// or a network-level failure that never reached the server.
+func (p RetryPolicy) shouldRetry(err error) bool {
+	var rerr RequestError
+	if errors.As(err, &rerr) {
+		return rerr.code == http.StatusTooManyRequests || rerr.code >= http.StatusInternalServerError
+	}
+
+	// http.Client.Do reports transport failures as *url.Error; anything else
+	// (e.g. a body that failed to decode) came back from the server.
+	var uerr *url.Error
+	if !errors.As(err, &uerr) {
+		return false
+	}
+
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// rateLimiter is a simple token bucket: it holds at most burst tokens and
+// refills at rps tokens per second.
+type rateLimiter struct {
+	mu         sync.Mutex
+	tokens     float64
+	burst      float64
+	rps        float64
+	lastRefill time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		tokens:     float64(burst),
+		burst:      float64(burst),
+		rps:        rps,
+		lastRefill: time.Now(),
+	}
+}
+
+// WithRateLimit caps outgoing requests to rps per second, allowing short
+// bursts of up to burst requests.
+func WithRateLimit(rps float64, burst int) optionF {
+	return func(hc *httpClient) error {
+		if rps <= 0 {
+			return newGeneralError("rate limit: rps must be positive")
+		}
+
+		if burst < 1 {
+			return newGeneralError("rate limit: burst must be at least 1")
+		}
+
+		hc.limiter = newRateLimiter(rps, burst)
+
+		return nil
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		l.refillLocked()
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+
+			return nil
+		}
+
+		missing := 1 - l.tokens
+		wait := time.Duration(missing / l.rps * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *rateLimiter) refillLocked() {
+	now := time.Now()
+	elapsed := now.Sub(l.lastRefill).Seconds()
+	l.lastRefill = now
+
+	l.tokens += elapsed * l.rps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// breakerState is the circuit breaker's current mode.
+type breakerState uint8
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker short-circuits calls after consecutive failures cross a
+// threshold, then allows a single probe request through once cooldown has
+// elapsed.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	state               breakerState
+	consecutiveFailures int
+
+	threshold int
+	cooldown  time.Duration
+	openedAt  time.Time
+}
+
+// WithCircuitBreaker opens the breaker after failures consecutive request
+// failures and keeps it open for cooldown before half-opening it again.
+func WithCircuitBreaker(failures int, cooldown time.Duration) optionF {
+	return func(hc *httpClient) error {
+		if failures < 1 {
+			return newGeneralError("circuit breaker: failures must be at least 1")
+		}
+
+		hc.breaker = &circuitBreaker{threshold: failures, cooldown: cooldown}
+
+		return nil
+	}
+}
+
+// Allow reports whether a request may proceed. A half-open breaker allows
+// exactly one probe through; its outcome decides whether the breaker closes
+// or reopens.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		b.state = breakerHalfOpen
+
+		return true
+	case breakerHalfOpen:
+		// A probe is already in flight; reject everyone else until
+		// RecordSuccess or RecordFailure resolves it.
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures = 0
+	b.state = breakerClosed
+}
+
+func (b *circuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	if b.consecutiveFailures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDoRequestRetriesUntilSuccess exercises the retry policy end to end
+// against a real server that fails a fixed number of times before
+// succeeding, the way proxy6 itself would during a transient 5xx blip.
+func TestDoRequestRetriesUntilSuccess(t *testing.T) {
+	const failTimes = 2
+
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failTimes {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"yes"}`))
+	}))
+	defer server.Close()
+
+	hc, err := NewHTTPClient(
+		context.Background(), "key",
+		WithAddr(server.URL),
+		WithRetry(RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, MaxAttempts: failTimes + 1}),
+	)
+	if err != nil {
+		t.Fatalf("building client: %v", err)
+	}
+
+	var dst baseResponse
+	if err := hc.call(context.Background(), "getproxy", nil, &dst); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != failTimes+1 {
+		t.Fatalf("requests = %d, want %d", got, failTimes+1)
+	}
+}
+
+// TestDoRequestGivesUpAfterMaxAttempts confirms the retry loop stops once
+// MaxAttempts is exhausted instead of retrying forever.
+func TestDoRequestGivesUpAfterMaxAttempts(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	hc, err := NewHTTPClient(
+		context.Background(), "key",
+		WithAddr(server.URL),
+		WithRetry(RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, MaxAttempts: 3}),
+	)
+	if err != nil {
+		t.Fatalf("building client: %v", err)
+	}
+
+	var dst baseResponse
+	if err := hc.call(context.Background(), "getproxy", nil, &dst); err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Fatalf("requests = %d, want 3", got)
+	}
+}
+
+// TestDoRequestRateLimitsEveryAttempt guards against the rate limiter being
+// consulted only once per call instead of once per attempt: with a
+// one-token burst and a retry policy that always fails, every retry should
+// still have to wait for a fresh token.
+func TestDoRequestRateLimitsEveryAttempt(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	const rps = 20.0
+
+	hc, err := NewHTTPClient(
+		context.Background(), "key",
+		WithAddr(server.URL),
+		WithRetry(RetryPolicy{BaseDelay: time.Microsecond, MaxDelay: time.Millisecond, MaxAttempts: 3}),
+		WithRateLimit(rps, 1),
+	)
+	if err != nil {
+		t.Fatalf("building client: %v", err)
+	}
+
+	begin := time.Now()
+
+	var dst baseResponse
+	if err := hc.call(context.Background(), "getproxy", nil, &dst); err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+
+	elapsed := time.Since(begin)
+
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Fatalf("requests = %d, want 3", got)
+	}
+
+	// Only the first attempt gets a free token from the burst; the other two
+	// must each wait out a 1/rps refill if the limiter is applied per
+	// attempt. If it were only applied once per call, this would return
+	// almost immediately.
+	minElapsed := time.Duration(float64(time.Second) / rps)
+	if elapsed < minElapsed {
+		t.Fatalf("elapsed = %v, want at least %v (rate limiter must gate every retry attempt)", elapsed, minElapsed)
+	}
+}
+
+// TestDoRequestBreakerStopsFurtherAttempts guards against the circuit
+// breaker being consulted only once per call instead of once per attempt:
+// once enough in-flight failures trip it, no further attempt in the same
+// call should reach the server.
+func TestDoRequestBreakerStopsFurtherAttempts(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	hc, err := NewHTTPClient(
+		context.Background(), "key",
+		WithAddr(server.URL),
+		WithRetry(RetryPolicy{BaseDelay: time.Microsecond, MaxDelay: time.Millisecond, MaxAttempts: 5}),
+		WithCircuitBreaker(2, time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("building client: %v", err)
+	}
+
+	var dst baseResponse
+	if err := hc.call(context.Background(), "getproxy", nil, &dst); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// threshold=2: attempts 1 and 2 fail and trip the breaker, so attempt 3
+	// should never reach the server.
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Fatalf("requests = %d, want 2 (breaker must stop further attempts once tripped)", got)
+	}
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	breaker := &circuitBreaker{threshold: 2, cooldown: 50 * time.Millisecond}
+
+	if !breaker.Allow() {
+		t.Fatal("breaker should start closed")
+	}
+
+	breaker.RecordFailure()
+	if !breaker.Allow() {
+		t.Fatal("breaker should stay closed below the threshold")
+	}
+
+	breaker.RecordFailure()
+	if breaker.Allow() {
+		t.Fatal("breaker should open once failures reach the threshold")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if !breaker.Allow() {
+		t.Fatal("breaker should half-open and allow a probe after cooldown")
+	}
+
+	breaker.RecordSuccess()
+	if !breaker.Allow() {
+		t.Fatal("breaker should close again after a successful probe")
+	}
+}
+
+// TestCircuitBreakerHalfOpenAllowsOnlyOneProbe guards against every
+// concurrent caller treating a half-open breaker as "allow", which would
+// let a failure storm through in lockstep with the very probe meant to
+// test recovery.
+func TestCircuitBreakerHalfOpenAllowsOnlyOneProbe(t *testing.T) {
+	breaker := &circuitBreaker{threshold: 1, cooldown: time.Millisecond}
+
+	breaker.RecordFailure()
+	if breaker.Allow() {
+		t.Fatal("breaker should be open immediately after tripping")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	probes := 0
+	for i := 0; i < 5; i++ {
+		if breaker.Allow() {
+			probes++
+		}
+	}
+
+	if probes != 1 {
+		t.Fatalf("probes allowed through half-open state = %d, want 1", probes)
+	}
+}
+
+func TestRateLimiterWaitBlocksUntilRefill(t *testing.T) {
+	limiter := newRateLimiter(10, 1)
+
+	ctx := context.Background()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	begin := time.Now()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("second wait: %v", err)
+	}
+
+	if elapsed := time.Since(begin); elapsed < 50*time.Millisecond {
+		t.Fatalf("elapsed = %v, want at least ~100ms for a token at 10rps", elapsed)
+	}
+}
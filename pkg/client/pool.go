@@ -0,0 +1,369 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ferux/proxysix/pkg/entities"
+	netproxy "golang.org/x/net/proxy"
+)
+
+// Strategy picks one proxy out of the pool's currently eligible entries for
+// a given outgoing request.
+type Strategy interface {
+	Select(host string, candidates []entities.Proxy) entities.Proxy
+}
+
+// RoundRobin cycles through candidates in order, ignoring host.
+func RoundRobin() Strategy { return &roundRobinStrategy{} }
+
+type roundRobinStrategy struct {
+	mu   sync.Mutex
+	next int
+}
+
+func (s *roundRobinStrategy) Select(_ string, candidates []entities.Proxy) entities.Proxy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	proxy := candidates[s.next%len(candidates)]
+	s.next++
+
+	return proxy
+}
+
+// Random picks a uniformly random candidate on every call.
+func Random() Strategy { return randomStrategy{} }
+
+type randomStrategy struct{}
+
+func (randomStrategy) Select(_ string, candidates []entities.Proxy) entities.Proxy {
+	return candidates[rand.Intn(len(candidates))]
+}
+
+// LeastRecentlyUsed favors the candidate that has gone the longest without
+// being selected, which spreads load evenly when requests arrive in bursts.
+func LeastRecentlyUsed() Strategy {
+	return &lruStrategy{lastUsed: make(map[string]time.Time)}
+}
+
+type lruStrategy struct {
+	mu       sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+func (s *lruStrategy) Select(_ string, candidates []entities.Proxy) entities.Proxy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldest := candidates[0]
+	oldestAt := s.lastUsed[oldest.ID]
+	for _, candidate := range candidates[1:] {
+		if usedAt := s.lastUsed[candidate.ID]; usedAt.Before(oldestAt) {
+			oldest, oldestAt = candidate, usedAt
+		}
+	}
+
+	s.lastUsed[oldest.ID] = time.Now()
+
+	return oldest
+}
+
+// StickyPerHost hashes the request's host so that every request to the same
+// destination keeps landing on the same proxy, so long as the set of
+// eligible proxies does not change; when it changes, a host may be mapped
+// to a different proxy.
+func StickyPerHost() Strategy { return stickyStrategy{} }
+
+type stickyStrategy struct{}
+
+func (stickyStrategy) Select(host string, candidates []entities.Proxy) entities.Proxy {
+	sum := sha256.Sum256([]byte(host))
+	idx := binary.BigEndian.Uint64(sum[:8]) % uint64(len(candidates))
+
+	return candidates[idx]
+}
+
+// quarantine tracks consecutive failures for a single proxy so the pool can
+// temporarily stop handing it out.
+type quarantine struct {
+	consecutiveFailures int
+	until               time.Time
+}
+
+// ProxyPool holds the account's proxies, applies a selection Strategy over
+// the ones currently eligible, and quarantines entries that keep failing.
+type ProxyPool struct {
+	mu       sync.RWMutex
+	proxies  map[string]entities.Proxy
+	strategy Strategy
+	dialer   *net.Dialer
+
+	quarantineAfter    int
+	quarantineCooldown time.Duration
+	failures           map[string]*quarantine
+}
+
+// PoolOptionF configures a ProxyPool at construction time.
+type PoolOptionF func(*ProxyPool) error
+
+// WithStrategy overrides the default RoundRobin selection strategy.
+func WithStrategy(strategy Strategy) PoolOptionF {
+	return func(p *ProxyPool) error {
+		if strategy == nil {
+			return newGeneralError("strategy is nil")
+		}
+
+		p.strategy = strategy
+
+		return nil
+	}
+}
+
+// WithDialer lets callers plug a custom net.Dialer (for timeouts, local
+// address binding, etc.) used when connecting to proxies.
+func WithDialer(dialer *net.Dialer) PoolOptionF {
+	return func(p *ProxyPool) error {
+		if dialer == nil {
+			return newGeneralError("dialer is nil")
+		}
+
+		p.dialer = dialer
+
+		return nil
+	}
+}
+
+// WithQuarantine quarantines a proxy for cooldown after failures consecutive
+// round-trip failures, so the pool stops handing it out until it cools down.
+func WithQuarantine(failures int, cooldown time.Duration) PoolOptionF {
+	return func(p *ProxyPool) error {
+		if failures <= 0 {
+			return newGeneralError("failures must be positive")
+		}
+
+		p.quarantineAfter = failures
+		p.quarantineCooldown = cooldown
+
+		return nil
+	}
+}
+
+// NewProxyPool builds a ProxyPool seeded with proxies. Pass ReplaceAll (or
+// construct a fresh pool) whenever the account's proxy list changes.
+func NewProxyPool(proxies []entities.Proxy, opts ...PoolOptionF) (*ProxyPool, error) {
+	pool := &ProxyPool{
+		proxies:            make(map[string]entities.Proxy, len(proxies)),
+		strategy:           RoundRobin(),
+		dialer:             &net.Dialer{Timeout: 30 * time.Second},
+		quarantineAfter:    3,
+		quarantineCooldown: time.Minute,
+		failures:           make(map[string]*quarantine),
+	}
+
+	for _, p := range proxies {
+		pool.proxies[p.ID] = p
+	}
+
+	for idx, opt := range opts {
+		if err := opt(pool); err != nil {
+			return nil, fmt.Errorf("applying %d opt: %w", idx, err)
+		}
+	}
+
+	return pool, nil
+}
+
+// ReplaceAll swaps the pool's contents, e.g. after a fresh ListProxies call.
+// Quarantine state for proxies that are still present is kept.
+func (p *ProxyPool) ReplaceAll(proxies []entities.Proxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fresh := make(map[string]entities.Proxy, len(proxies))
+	for _, proxy := range proxies {
+		fresh[proxy.ID] = proxy
+	}
+
+	for id := range p.failures {
+		if _, ok := fresh[id]; !ok {
+			delete(p.failures, id)
+		}
+	}
+
+	p.proxies = fresh
+}
+
+// eligible returns the proxies that are active, not expired, and not
+// currently quarantined.
+func (p *ProxyPool) eligible() []entities.Proxy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	now := time.Now()
+	candidates := make([]entities.Proxy, 0, len(p.proxies))
+	for id, proxy := range p.proxies {
+		if !proxy.Active || proxy.ExpireDate.Before(now) {
+			continue
+		}
+
+		if q, ok := p.failures[id]; ok && now.Before(q.until) {
+			continue
+		}
+
+		candidates = append(candidates, proxy)
+	}
+
+	// Map iteration order is random; sort so order-based strategies see a
+	// stable list.
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+
+	return candidates
+}
+
+// ErrPoolExhausted is returned when no proxy is currently eligible to serve
+// a request.
+var ErrPoolExhausted = newGeneralError("proxy pool: no eligible proxies")
+
+// Pick selects one eligible proxy for host using the pool's Strategy.
+func (p *ProxyPool) Pick(host string) (entities.Proxy, error) {
+	candidates := p.eligible()
+	if len(candidates) == 0 {
+		return entities.Proxy{}, ErrPoolExhausted
+	}
+
+	return p.strategy.Select(host, candidates), nil
+}
+
+// ReportSuccess clears any accumulated failures for the proxy.
+func (p *ProxyPool) ReportSuccess(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.failures, id)
+}
+
+// ReportFailure records a failed round-trip through the proxy and
+// quarantines it once it crosses the configured threshold.
+func (p *ProxyPool) ReportFailure(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	q, ok := p.failures[id]
+	if !ok {
+		q = &quarantine{}
+		p.failures[id] = q
+	}
+
+	q.consecutiveFailures++
+	if q.consecutiveFailures >= p.quarantineAfter {
+		q.until = time.Now().Add(p.quarantineCooldown)
+	}
+}
+
+// PoolTransport is an http.RoundTripper that spreads outgoing requests
+// across a ProxyPool, building the right upstream transport (plain HTTP
+// proxy or SOCKS5) per selected entry.
+type PoolTransport struct {
+	pool *ProxyPool
+
+	mu         sync.Mutex
+	transports map[string]http.RoundTripper
+}
+
+// NewPoolTransport wraps pool as an http.RoundTripper.
+func NewPoolTransport(pool *ProxyPool) *PoolTransport {
+	return &PoolTransport{
+		pool:       pool,
+		transports: make(map[string]http.RoundTripper),
+	}
+}
+
+func (t *PoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	selected, err := t.pool.Pick(req.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := t.transportFor(selected)
+	if err != nil {
+		t.pool.ReportFailure(selected.ID)
+
+		return nil, err
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.pool.ReportFailure(selected.ID)
+
+		return nil, err
+	}
+
+	t.pool.ReportSuccess(selected.ID)
+
+	return resp, nil
+}
+
+func (t *PoolTransport) transportFor(p entities.Proxy) (http.RoundTripper, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if rt, ok := t.transports[p.ID]; ok {
+		return rt, nil
+	}
+
+	proxyURL, err := url.Parse(p.ProxyURL())
+	if err != nil {
+		return nil, newGeneralError("parsing proxy url: " + err.Error())
+	}
+
+	var rt http.RoundTripper
+	switch p.Type {
+	case entities.ProxyTypeHTTP:
+		rt = &http.Transport{
+			Proxy:       http.ProxyURL(proxyURL),
+			DialContext: t.pool.dialer.DialContext,
+		}
+	case entities.ProxyTypeSocks:
+		socksDialer, err := netproxy.FromURL(proxyURL, t.pool.dialer)
+		if err != nil {
+			return nil, newGeneralError("building socks5 dialer: " + err.Error())
+		}
+
+		rt = &http.Transport{
+			Dial: socksDialer.Dial,
+		}
+	default:
+		return nil, newGeneralError("unsupported proxy type for pool transport")
+	}
+
+	t.transports[p.ID] = rt
+
+	return rt, nil
+}
+
+// Dialer picks one proxy from the pool for host and returns a proxy.Dialer
+// that connects through it, for callers that need a raw net.Conn rather
+// than an http.Client. Only SOCKS5 entries are supported; if an HTTP proxy
+// is picked, an error is returned.
+func (t *PoolTransport) Dialer(host string) (netproxy.Dialer, error) {
+	selected, err := t.pool.Pick(host)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyURL, err := url.Parse(selected.ProxyURL())
+	if err != nil {
+		return nil, newGeneralError("parsing proxy url: " + err.Error())
+	}
+
+	return netproxy.FromURL(proxyURL, t.pool.dialer)
+}
@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// GetLoggerSlog adapts slog's ambient default logger to LoggerV2.
+func GetLoggerSlog(_ context.Context) Logger {
+	return WrapSlog(slog.Default())
+}
+
+// WrapSlog adapts an existing *slog.Logger to LoggerV2, so a proxy6 client
+// can feed its traces into any slog handler (including OpenTelemetry
+// exporters wired up as a slog.Handler).
+func WrapSlog(log *slog.Logger) LoggerV2 {
+	return slogWrapper{log: log}
+}
+
+type slogWrapper struct {
+	log *slog.Logger
+}
+
+func (w slogWrapper) Debug(msg string, args ...KeyValue) {
+	w.log.Debug(msg, toSlogArgs(args)...)
+}
+
+func (w slogWrapper) Info(msg string, args ...KeyValue) {
+	w.log.Info(msg, toSlogArgs(args)...)
+}
+
+func (w slogWrapper) Warn(msg string, args ...KeyValue) {
+	w.log.Warn(msg, toSlogArgs(args)...)
+}
+
+func (w slogWrapper) Error(msg string, args ...KeyValue) {
+	w.log.Error(msg, toSlogArgs(args)...)
+}
+
+func (w slogWrapper) With(fields ...KeyValue) LoggerV2 {
+	return slogWrapper{log: w.log.With(toSlogArgs(fields)...)}
+}
+
+func (w slogWrapper) StartSpan(ctx context.Context, name string) (context.Context, EndFunc) {
+	begin := time.Now()
+	scoped := w.log.With("span", name)
+
+	return ctx, func(fields ...KeyValue) {
+		args := append([]any{"duration", time.Since(begin)}, toSlogArgs(fields)...)
+		scoped.Info("span end", args...)
+	}
+}
+
+func toSlogArgs(fields []KeyValue) []any {
+	args := make([]any, 0, len(fields)*2)
+	for _, field := range fields {
+		args = append(args, field.Key, field.Value)
+	}
+
+	return args
+}
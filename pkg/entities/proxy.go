@@ -45,6 +45,37 @@ func ParseProxyType(value string) (ProxyType, error) {
 	}
 }
 
+// ProxyVersion distinguishes the IP stack a proxy was issued on.
+type ProxyVersion uint8
+
+const (
+	ProxyVersionUnknown ProxyVersion = iota
+	ProxyVersionIPv4
+	ProxyVersionIPv6
+)
+
+func (v ProxyVersion) MarshalText() ([]byte, error) {
+	switch v {
+	case ProxyVersionIPv4:
+		return []byte("4"), nil
+	case ProxyVersionIPv6:
+		return []byte("6"), nil
+	default:
+		return nil, fmt.Errorf("version %d: %w", v, ErrWrongProxyType)
+	}
+}
+
+func ParseProxyVersion(value string) (ProxyVersion, error) {
+	switch value {
+	case "4":
+		return ProxyVersionIPv4, nil
+	case "6":
+		return ProxyVersionIPv6, nil
+	default:
+		return ProxyVersionUnknown, fmt.Errorf("version %s: %w", value, ErrWrongProxyType)
+	}
+}
+
 type Proxy struct {
 	ID          string
 	Host        string
@@ -52,11 +83,13 @@ type Proxy struct {
 	User        string
 	Password    Sensitive[string]
 	Type        ProxyType
+	Version     ProxyVersion
 	Country     string
 	Date        time.Time
 	ExpireDate  time.Time
 	Description string
 	Active      bool
+	AutoRenew   bool
 }
 
 func (p *Proxy) ProxyURL() string {
@@ -93,3 +126,15 @@ func (s Sensitive[T]) MarshalText() (text []byte, err error) {
 func (s *Sensitive[T]) Allow() {
 	s.allow = true
 }
+
+// Reveal returns the wrapped value, gated by the same Allow flag that
+// controls MarshalText: callers must opt in via Allow before the secret
+// leaves the wrapper through either path. ok is false if Allow has not
+// been called.
+func (s Sensitive[T]) Reveal() (value T, ok bool) {
+	if !s.allow {
+		return value, false
+	}
+
+	return s.value, true
+}
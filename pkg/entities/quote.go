@@ -0,0 +1,22 @@
+package entities
+
+// PriceQuote is the price breakdown returned by the getprice action for a
+// given count/period/version combination.
+type PriceQuote struct {
+	Price       float64
+	PriceSingle float64
+	Period      int
+	Count       int
+}
+
+// ProxyCheck is the result of probing a single proxy through the check
+// action: whether proxy6 itself considers it reachable right now.
+type ProxyCheck struct {
+	ID string
+	OK bool
+}
+
+// Country is a country available for purchase, as returned by getcountry.
+type Country struct {
+	Code string
+}
@@ -0,0 +1,252 @@
+// Package health runs background probes against a set of proxies and
+// tracks their latency and success rate over time. It is a natural
+// companion to client.ProxyPool: the pool can quarantine or reweight
+// entries based on the HealthEvents this package emits.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ferux/proxysix/pkg/entities"
+)
+
+// ProbeType selects how a proxy's reachability is verified.
+type ProbeType uint8
+
+const (
+	ProbeTCP ProbeType = iota
+	ProbeHTTP
+	ProbeDNS
+)
+
+func ParseProbeType(value string) (ProbeType, error) {
+	switch value {
+	case "tcp", "":
+		return ProbeTCP, nil
+	case "http":
+		return ProbeHTTP, nil
+	case "dns":
+		return ProbeDNS, nil
+	default:
+		return ProbeTCP, fmt.Errorf("probe %s: unsupported", value)
+	}
+}
+
+// Config tunes how a Checker probes its proxies.
+type Config struct {
+	// Concurrency bounds how many proxies are probed at once.
+	Concurrency int
+	// Interval is how often Run repeats the probe round. Unused by Check.
+	Interval time.Duration
+	// Timeout bounds a single proxy's probe.
+	Timeout time.Duration
+
+	Probe ProbeType
+	// CanaryURL is fetched through the proxy when Probe is ProbeHTTP.
+	CanaryURL string
+	// CanaryHost is resolved through the proxy when Probe is ProbeDNS.
+	CanaryHost string
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 8
+	}
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	if cfg.Probe == ProbeHTTP && cfg.CanaryURL == "" {
+		cfg.CanaryURL = "https://api.ipify.org"
+	}
+
+	if cfg.Probe == ProbeDNS && cfg.CanaryHost == "" {
+		cfg.CanaryHost = "proxy6.net"
+	}
+
+	return cfg
+}
+
+// HealthEvent reports the outcome of a single proxy probe.
+type HealthEvent struct {
+	ProxyID  string
+	OK       bool
+	Latency  time.Duration
+	Err      error
+	EgressIP string
+}
+
+// Result is the accumulated history for one proxy across every probe round.
+type Result struct {
+	ProxyID       string
+	Checks        int
+	Successes     int
+	LastOK        bool
+	LastLatency   time.Duration
+	LastErr       error
+	LastEgressIP  string
+	LastCheckedAt time.Time
+}
+
+// SuccessRate is Successes/Checks, or 0 before the first probe.
+func (r Result) SuccessRate() float64 {
+	if r.Checks == 0 {
+		return 0
+	}
+
+	return float64(r.Successes) / float64(r.Checks)
+}
+
+// Checker probes a fixed set of proxies on a worker pool bounded by
+// Config.Concurrency.
+type Checker struct {
+	proxies []entities.Proxy
+	cfg     Config
+	events  chan HealthEvent
+
+	mu      sync.RWMutex
+	results map[string]*Result
+}
+
+// New builds a Checker over proxies. Unset Config fields fall back to
+// sane defaults (8-way concurrency, 1 minute interval, 5s timeout, TCP
+// probe).
+func New(proxies []entities.Proxy, cfg Config) *Checker {
+	cfg = cfg.withDefaults()
+
+	return &Checker{
+		proxies: proxies,
+		cfg:     cfg,
+		events:  make(chan HealthEvent, cfg.Concurrency*2),
+		results: make(map[string]*Result, len(proxies)),
+	}
+}
+
+// Events returns the channel HealthEvents are published to. Sends are
+// non-blocking: a slow or absent consumer misses events rather than
+// stalling the probe round, so callers that need every event should drain
+// it promptly from a separate goroutine.
+func (c *Checker) Events() <-chan HealthEvent {
+	return c.events
+}
+
+// Snapshot returns the current accumulated Result per proxy ID.
+func (c *Checker) Snapshot() map[string]Result {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := make(map[string]Result, len(c.results))
+	for id, result := range c.results {
+		out[id] = *result
+	}
+
+	return out
+}
+
+// Check runs a single probe round across all proxies and returns every
+// HealthEvent it produced, in proxy order.
+func (c *Checker) Check(ctx context.Context) []HealthEvent {
+	sem := make(chan struct{}, c.cfg.Concurrency)
+	events := make([]HealthEvent, len(c.proxies))
+
+	var wg sync.WaitGroup
+	for idx, proxy := range c.proxies {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(idx int, proxy entities.Proxy) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			event := c.probe(ctx, proxy)
+			c.record(event)
+			events[idx] = event
+
+			select {
+			case c.events <- event:
+			default:
+			}
+		}(idx, proxy)
+	}
+
+	wg.Wait()
+
+	return events
+}
+
+// Run probes every proxy immediately, then again every Config.Interval,
+// until ctx is done. It closes Events() before returning.
+func (c *Checker) Run(ctx context.Context) {
+	defer close(c.events)
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	c.Check(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+func (c *Checker) probe(ctx context.Context, proxy entities.Proxy) HealthEvent {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	begin := time.Now()
+
+	var egressIP string
+	var err error
+
+	switch c.cfg.Probe {
+	case ProbeHTTP:
+		egressIP, err = probeHTTP(ctx, proxy, c.cfg.CanaryURL)
+	case ProbeDNS:
+		err = probeDNS(ctx, proxy, c.cfg.CanaryHost)
+	default:
+		err = probeTCP(ctx, proxy)
+	}
+
+	return HealthEvent{
+		ProxyID:  proxy.ID,
+		OK:       err == nil,
+		Latency:  time.Since(begin),
+		Err:      err,
+		EgressIP: egressIP,
+	}
+}
+
+func (c *Checker) record(event HealthEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results[event.ProxyID]
+	if !ok {
+		result = &Result{ProxyID: event.ProxyID}
+		c.results[event.ProxyID] = result
+	}
+
+	result.Checks++
+	if event.OK {
+		result.Successes++
+	}
+
+	result.LastOK = event.OK
+	result.LastLatency = event.Latency
+	result.LastErr = event.Err
+	result.LastEgressIP = event.EgressIP
+	result.LastCheckedAt = time.Now()
+}
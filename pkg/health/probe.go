@@ -0,0 +1,170 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ferux/proxysix/pkg/entities"
+	"golang.org/x/net/proxy"
+)
+
+// probeTCP succeeds once a TCP connection to the proxy's own listener is
+// established, without routing any traffic through it.
+func probeTCP(ctx context.Context, p entities.Proxy) error {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(p.Host, fmt.Sprint(p.Port)))
+	if err != nil {
+		return fmt.Errorf("dialing proxy: %w", err)
+	}
+
+	return conn.Close()
+}
+
+// probeHTTP fetches canaryURL through p and returns whatever body it
+// returned, trimmed, as the detected egress IP. Canary services such as
+// https://api.ipify.org respond with exactly that.
+func probeHTTP(ctx context.Context, p entities.Proxy, canaryURL string) (string, error) {
+	proxyURL, err := url.Parse(p.ProxyURL())
+	if err != nil {
+		return "", fmt.Errorf("parsing proxy url: %w", err)
+	}
+
+	transport := &http.Transport{}
+
+	switch p.Type {
+	case entities.ProxyTypeHTTP:
+		transport.Proxy = http.ProxyURL(proxyURL)
+	case entities.ProxyTypeSocks:
+		socksDialer, err := proxy.FromURL(proxyURL, &net.Dialer{})
+		if err != nil {
+			return "", fmt.Errorf("building socks5 dialer: %w", err)
+		}
+
+		transport.Dial = socksDialer.Dial
+	default:
+		return "", fmt.Errorf("proxy type %d: unsupported for http probe", p.Type)
+	}
+
+	hclient := &http.Client{Transport: transport}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, canaryURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("building canary request: %w", err)
+	}
+
+	resp, err := hclient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching canary url: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("canary url returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", fmt.Errorf("reading canary response: %w", err)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// probeDNS resolves canaryHost through p, proving the proxy can still reach
+// the open internet rather than just accepting connections.
+func probeDNS(ctx context.Context, p entities.Proxy, canaryHost string) error {
+	dialer, err := dialerFor(p)
+	if err != nil {
+		return err
+	}
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		// Always tunnel over TCP: SOCKS5 and CONNECT carry streams only, and
+		// the resolver switches to TCP framing for non-packet conns.
+		Dial: func(ctx context.Context, _, address string) (net.Conn, error) {
+			return dialer.Dial("tcp", address)
+		},
+	}
+
+	if _, err := resolver.LookupHost(ctx, canaryHost); err != nil {
+		return fmt.Errorf("resolving %s through proxy: %w", canaryHost, err)
+	}
+
+	return nil
+}
+
+// dialerFor returns a proxy.Dialer that routes through p, for probes that
+// need a raw connection rather than an http.Client.
+func dialerFor(p entities.Proxy) (proxy.Dialer, error) {
+	proxyURL, err := url.Parse(p.ProxyURL())
+	if err != nil {
+		return nil, fmt.Errorf("parsing proxy url: %w", err)
+	}
+
+	switch p.Type {
+	case entities.ProxyTypeSocks:
+		return proxy.FromURL(proxyURL, &net.Dialer{})
+	case entities.ProxyTypeHTTP:
+		return httpConnectDialer{proxyURL: proxyURL}, nil
+	default:
+		return nil, fmt.Errorf("proxy type %d: unsupported for dialing", p.Type)
+	}
+}
+
+// httpConnectDialer tunnels arbitrary TCP connections through an HTTP proxy
+// via the CONNECT method, the way an http.Transport would for an https
+// target, so non-HTTP probes (DNS) can still ride the proxy's own tunnel.
+type httpConnectDialer struct {
+	proxyURL *url.URL
+}
+
+func (d httpConnectDialer) Dial(network, addr string) (net.Conn, error) {
+	var netDialer net.Dialer
+
+	conn, err := netDialer.Dial(network, d.proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("dialing proxy: %w", err)
+	}
+
+	connectReq := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+
+	if user := d.proxyURL.User; user != nil {
+		password, _ := user.Password()
+		connectReq.SetBasicAuth(user.Username(), password)
+	}
+
+	if err := connectReq.Write(conn); err != nil {
+		conn.Close()
+
+		return nil, fmt.Errorf("writing connect request: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), connectReq)
+	if err != nil {
+		conn.Close()
+
+		return nil, fmt.Errorf("reading connect response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+
+		return nil, fmt.Errorf("proxy CONNECT failed: %s", resp.Status)
+	}
+
+	return conn, nil
+}
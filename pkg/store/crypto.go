@@ -0,0 +1,60 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+)
+
+// deriveKey turns an arbitrary-length config secret into a 32 byte AES-256
+// key. It is not a substitute for a real KDF (scrypt/argon2) but matches
+// the rest of this package's dependency-free approach.
+func deriveKey(secret string) [32]byte {
+	return sha256.Sum256([]byte(secret))
+}
+
+func seal(key [32]byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, newStoreError("building cipher: " + err.Error())
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, newStoreError("building gcm: " + err.Error())
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, newStoreError("generating nonce: " + err.Error())
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key [32]byte, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, newStoreError("building cipher: " + err.Error())
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, newStoreError("building gcm: " + err.Error())
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, newStoreError("ciphertext shorter than nonce")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, newStoreError("decrypting: " + err.Error())
+	}
+
+	return plaintext, nil
+}
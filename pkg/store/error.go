@@ -0,0 +1,13 @@
+package store
+
+func newStoreError(msg string) error {
+	return storeError{msg: msg}
+}
+
+type storeError struct {
+	msg string
+}
+
+func (err storeError) Error() string {
+	return err.msg
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// KV is the minimal persistence contract Store needs. It is intentionally
+// narrow so a SQLite, BoltDB, or remote KV backend can stand in for the
+// bundled FileKV without touching Store itself.
+type KV interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Put(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, key string) error
+	Keys(ctx context.Context) ([]string, error)
+}
+
+// FileKV is a dependency-free KV backend that keeps everything in memory
+// and flushes the whole map to a single JSON file on every write. It is
+// meant for CLI-sized proxy lists, not as a general purpose database.
+type FileKV struct {
+	path string
+
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+// NewFileKV loads path if it exists and returns a ready to use FileKV.
+func NewFileKV(path string) (*FileKV, error) {
+	kv := &FileKV{path: path, data: make(map[string][]byte)}
+
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return kv, nil
+		}
+
+		return nil, newStoreError("reading file kv: " + err.Error())
+	}
+
+	if len(payload) == 0 {
+		return kv, nil
+	}
+
+	if err := json.Unmarshal(payload, &kv.data); err != nil {
+		return nil, newStoreError("decoding file kv: " + err.Error())
+	}
+
+	return kv, nil
+}
+
+func (kv *FileKV) Get(_ context.Context, key string) ([]byte, bool, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	value, ok := kv.data[key]
+
+	return value, ok, nil
+}
+
+func (kv *FileKV) Put(_ context.Context, key string, value []byte) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	kv.data[key] = value
+
+	return kv.flushLocked()
+}
+
+func (kv *FileKV) Delete(_ context.Context, key string) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	delete(kv.data, key)
+
+	return kv.flushLocked()
+}
+
+func (kv *FileKV) Keys(_ context.Context) ([]string, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	keys := make([]string, 0, len(kv.data))
+	for key := range kv.data {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (kv *FileKV) flushLocked() error {
+	payload, err := json.Marshal(kv.data)
+	if err != nil {
+		return newStoreError("encoding file kv: " + err.Error())
+	}
+
+	if err := os.WriteFile(kv.path, payload, 0o600); err != nil {
+		return newStoreError("writing file kv: " + err.Error())
+	}
+
+	return nil
+}
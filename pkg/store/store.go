@@ -0,0 +1,319 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferux/proxysix/pkg/client"
+	"github.com/ferux/proxysix/pkg/entities"
+)
+
+// Store caches the account's proxies locally so CLI users and long-running
+// agents can query them without hitting proxy6 on every operation.
+type Store struct {
+	kv  KV
+	key [32]byte
+}
+
+// New builds a Store backed by kv. secret seeds the key used to encrypt
+// each proxy's password at rest; passwords are only ever decrypted via
+// Reveal.
+func New(kv KV, secret string) *Store {
+	return &Store{
+		kv:  kv,
+		key: deriveKey(secret),
+	}
+}
+
+// record is the on-disk shape of a cached proxy: everything Proxy carries,
+// except the password, which is stored as an AES-GCM sealed blob instead of
+// going through entities.Sensitive's masked MarshalText.
+type record struct {
+	ID          string
+	Host        string
+	Port        uint16
+	User        string
+	EncPassword []byte
+	Type        entities.ProxyType
+	Version     entities.ProxyVersion
+	Country     string
+	Date        time.Time
+	ExpireDate  time.Time
+	Description string
+	Active      bool
+	AutoRenew   bool
+}
+
+func (s *Store) toRecord(proxy entities.Proxy) (record, error) {
+	proxy.Password.Allow()
+
+	plain, ok := proxy.Password.Reveal()
+	if !ok {
+		return record{}, fmt.Errorf("revealing password for proxy %s", proxy.ID)
+	}
+
+	enc, err := seal(s.key, []byte(plain))
+	if err != nil {
+		return record{}, fmt.Errorf("sealing password: %w", err)
+	}
+
+	return record{
+		ID:          proxy.ID,
+		Host:        proxy.Host,
+		Port:        proxy.Port,
+		User:        proxy.User,
+		EncPassword: enc,
+		Type:        proxy.Type,
+		Version:     proxy.Version,
+		Country:     proxy.Country,
+		Date:        proxy.Date,
+		ExpireDate:  proxy.ExpireDate,
+		Description: proxy.Description,
+		Active:      proxy.Active,
+		AutoRenew:   proxy.AutoRenew,
+	}, nil
+}
+
+// toProxy maps a record back to entities.Proxy. The password stays masked
+// (an empty, non-allowed Sensitive) unless reveal is true.
+func (s *Store) toProxy(rec record, reveal bool) (entities.Proxy, error) {
+	password := entities.NewSensitive("")
+	if reveal {
+		plain, err := open(s.key, rec.EncPassword)
+		if err != nil {
+			return entities.Proxy{}, fmt.Errorf("opening password: %w", err)
+		}
+
+		password = entities.NewSensitive(string(plain))
+		password.Allow()
+	}
+
+	return entities.Proxy{
+		ID:          rec.ID,
+		Host:        rec.Host,
+		Port:        rec.Port,
+		User:        rec.User,
+		Password:    password,
+		Type:        rec.Type,
+		Version:     rec.Version,
+		Country:     rec.Country,
+		Date:        rec.Date,
+		ExpireDate:  rec.ExpireDate,
+		Description: rec.Description,
+		Active:      rec.Active,
+		AutoRenew:   rec.AutoRenew,
+	}, nil
+}
+
+func recordKey(id string) string { return "proxy/" + id }
+
+// UpsertMany stores or updates proxies, keyed by their ID.
+func (s *Store) UpsertMany(ctx context.Context, proxies []entities.Proxy) error {
+	for _, proxy := range proxies {
+		rec, err := s.toRecord(proxy)
+		if err != nil {
+			return fmt.Errorf("proxy %s: %w", proxy.ID, err)
+		}
+
+		payload, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("encoding proxy %s: %w", proxy.ID, err)
+		}
+
+		if err := s.kv.Put(ctx, recordKey(proxy.ID), payload); err != nil {
+			return fmt.Errorf("storing proxy %s: %w", proxy.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) all(ctx context.Context) ([]record, error) {
+	keys, err := s.kv.Keys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing keys: %w", err)
+	}
+
+	records := make([]record, 0, len(keys))
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "proxy/") {
+			continue
+		}
+
+		payload, ok, err := s.kv.Get(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", key, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", key, err)
+		}
+
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// ListAll returns every cached proxy, with passwords masked.
+func (s *Store) ListAll(ctx context.Context) ([]entities.Proxy, error) {
+	return s.query(ctx, func(record) bool { return true })
+}
+
+// GetByCountry returns the cached proxies issued for the given country.
+func (s *Store) GetByCountry(ctx context.Context, country string) ([]entities.Proxy, error) {
+	return s.query(ctx, func(rec record) bool {
+		return strings.EqualFold(rec.Country, country)
+	})
+}
+
+// ExpiringWithin returns the cached proxies whose ExpireDate is before
+// now+d, including proxies that have already expired.
+func (s *Store) ExpiringWithin(ctx context.Context, d time.Duration) ([]entities.Proxy, error) {
+	deadline := time.Now().Add(d)
+
+	return s.query(ctx, func(rec record) bool {
+		return rec.ExpireDate.Before(deadline)
+	})
+}
+
+// Search returns the cached proxies whose description contains descr,
+// case-insensitively.
+func (s *Store) Search(ctx context.Context, descr string) ([]entities.Proxy, error) {
+	needle := strings.ToLower(descr)
+
+	return s.query(ctx, func(rec record) bool {
+		return strings.Contains(strings.ToLower(rec.Description), needle)
+	})
+}
+
+func (s *Store) query(ctx context.Context, match func(record) bool) ([]entities.Proxy, error) {
+	records, err := s.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	proxies := make([]entities.Proxy, 0, len(records))
+	for _, rec := range records {
+		if !match(rec) {
+			continue
+		}
+
+		proxy, err := s.toProxy(rec, false)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %s: %w", rec.ID, err)
+		}
+
+		proxies = append(proxies, proxy)
+	}
+
+	return proxies, nil
+}
+
+// Reveal looks up a single cached proxy by ID and decrypts its password,
+// returning a Proxy whose Password.Allow has already been called.
+func (s *Store) Reveal(ctx context.Context, id string) (entities.Proxy, error) {
+	payload, ok, err := s.kv.Get(ctx, recordKey(id))
+	if err != nil {
+		return entities.Proxy{}, fmt.Errorf("reading proxy %s: %w", id, err)
+	}
+
+	if !ok {
+		return entities.Proxy{}, newStoreError("proxy " + id + " not found")
+	}
+
+	var rec record
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		return entities.Proxy{}, fmt.Errorf("decoding proxy %s: %w", id, err)
+	}
+
+	return s.toProxy(rec, true)
+}
+
+// ChangeKind classifies a Sync observation against the previous cache
+// contents.
+type ChangeKind uint8
+
+const (
+	ChangeUnknown ChangeKind = iota
+	ChangeAdded
+	ChangeRemoved
+	ChangeExpiring
+)
+
+// ChangeEvent reports one difference Sync observed between the cache and
+// the latest API snapshot.
+type ChangeEvent struct {
+	Kind  ChangeKind
+	Proxy entities.Proxy
+}
+
+// Sync refreshes the store from the API and returns what changed. A proxy
+// is reported as ChangeExpiring when it survives the sync but now expires
+// within 24 hours.
+func (s *Store) Sync(ctx context.Context, c client.Client, params client.ListProxyParams) ([]ChangeEvent, error) {
+	before, err := s.all(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading cache: %w", err)
+	}
+
+	beforeByID := make(map[string]record, len(before))
+	for _, rec := range before {
+		beforeByID[rec.ID] = rec
+	}
+
+	proxies, err := c.ListProxies(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("listing proxies: %w", err)
+	}
+
+	if err := s.UpsertMany(ctx, proxies); err != nil {
+		return nil, fmt.Errorf("caching proxies: %w", err)
+	}
+
+	afterByID := make(map[string]entities.Proxy, len(proxies))
+	events := make([]ChangeEvent, 0, len(proxies))
+
+	const expiringSoon = 24 * time.Hour
+
+	for _, proxy := range proxies {
+		afterByID[proxy.ID] = proxy
+
+		if _, existed := beforeByID[proxy.ID]; !existed {
+			events = append(events, ChangeEvent{Kind: ChangeAdded, Proxy: proxy})
+
+			continue
+		}
+
+		if time.Until(proxy.ExpireDate) < expiringSoon {
+			events = append(events, ChangeEvent{Kind: ChangeExpiring, Proxy: proxy})
+		}
+	}
+
+	for id, rec := range beforeByID {
+		if _, stillPresent := afterByID[id]; stillPresent {
+			continue
+		}
+
+		proxy, err := s.toProxy(rec, false)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %s: %w", id, err)
+		}
+
+		if err := s.kv.Delete(ctx, recordKey(id)); err != nil {
+			return nil, fmt.Errorf("removing stale proxy %s: %w", id, err)
+		}
+
+		events = append(events, ChangeEvent{Kind: ChangeRemoved, Proxy: proxy})
+	}
+
+	return events, nil
+}